@@ -5,11 +5,15 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/afero"
 
+	"github.com/masaushi/accessory/internal/config"
 	"github.com/masaushi/accessory/internal/generator"
 	"github.com/masaushi/accessory/internal/parser"
+	"github.com/masaushi/accessory/internal/types"
 )
 
 const Version = "dev"
@@ -32,9 +36,14 @@ func Execute(fs afero.Fs, args []string) {
 	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
 	flags.Usage = usage
 	version := flags.Bool("version", false, "show the version of accessory")
-	typeName := flags.String("type", "", "type name; must be set")
+	typeName := flags.String("type", "", "type name; if unset, generation is driven by a .accessory.yml config file")
 	receiver := flags.String("receiver", "", "receiver name; default first letter of type name")
 	output := flags.String("output", "", "output file name; default <type_name>_accessor.go")
+	getterStyle := flags.String("getter-style", "", "default getter style for pointer fields; \"safe\" returns the zero value for a nil pointer to a basic scalar, and the pointer itself (nil-safe on the receiver) for other pointer fields")
+	configPath := flags.String("config", "", "path to a .accessory.yml config file; default <directory>/.accessory.yml")
+	pluginNames := flags.String("plugins", "", "comma-separated list of extra generator plugins to run, e.g. \"builder\"")
+	concurrent := flags.Bool("concurrent", false, "generate thread-safe accessors guarded by an embedded accessoryLock")
+	autoEmbedLock := flags.Bool("auto-embed-lock", false, "rewrite the source struct to embed accessoryLock if -concurrent requires it and it's missing")
 
 	if err := flags.Parse(args[1:]); err != nil {
 		flags.Usage()
@@ -46,11 +55,6 @@ func Execute(fs afero.Fs, args []string) {
 		os.Exit(0)
 	}
 
-	if typeName == nil || len(*typeName) == 0 {
-		flags.Usage()
-		os.Exit(1)
-	}
-
 	var dir string
 	if cliArgs := flags.Args(); len(cliArgs) > 0 {
 		dir = cliArgs[0]
@@ -65,6 +69,13 @@ func Execute(fs afero.Fs, args []string) {
 		os.Exit(1)
 	}
 
+	if len(*typeName) == 0 {
+		if err := executeFromConfig(fs, flags, dir, *configPath); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	pkg, err := parser.ParsePackage(dir)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -72,11 +83,187 @@ func Execute(fs afero.Fs, args []string) {
 		os.Exit(1)
 	}
 
-	if err = generator.Generate(fs, pkg, *typeName, *output, *receiver); err != nil {
+	opts := generator.Options{
+		TypeName:      *typeName,
+		Output:        *output,
+		Receiver:      *receiver,
+		GetterStyle:   *getterStyle,
+		Plugins:       splitPlugins(*pluginNames),
+		Concurrent:    *concurrent,
+		AutoEmbedLock: *autoEmbedLock,
+	}
+	if err = generateType(fs, dir, pkg, opts); err != nil {
 		log.Fatal(err)
 	}
 }
 
+// generateType runs the full generation flow for a single type: it makes
+// sure a struct that needs a thread-safe accessor embeds accessoryLock
+// (auto-injecting it or failing, per opts.AutoEmbedLock), writes the lock
+// helper file when needed, and finally generates the accessors.
+func generateType(fs afero.Fs, dir string, pkg *types.Package, opts generator.Options) error {
+	pkg, needsLock, err := ensureLockEmbed(dir, pkg, opts)
+	if err != nil {
+		return err
+	}
+	if needsLock {
+		if err := generator.WriteLockHelper(fs, pkg); err != nil {
+			return err
+		}
+	}
+
+	return generator.Generate(fs, pkg, opts)
+}
+
+// ensureLockEmbed checks whether opts requires a thread-safe accessor for
+// opts.TypeName and, if so, that the struct embeds accessoryLock. When the
+// embed is missing it either auto-injects it (opts.AutoEmbedLock) and
+// re-parses the package, or returns a clear error.
+func ensureLockEmbed(dir string, pkg *types.Package, opts generator.Options) (*types.Package, bool, error) {
+	st := findStruct(pkg, opts.TypeName)
+	if st == nil {
+		return pkg, false, nil
+	}
+
+	needsLock := opts.Concurrent
+	for _, field := range st.Fields {
+		if field.Tag != nil && field.Tag.Lock {
+			needsLock = true
+		}
+	}
+	if !needsLock || st.EmbedsType("accessoryLock") {
+		return pkg, needsLock, nil
+	}
+
+	if !opts.AutoEmbedLock {
+		return nil, false, fmt.Errorf(
+			"type %s must embed accessoryLock to use thread-safe accessors; "+
+				"add it as an embedded field or pass -auto-embed-lock", opts.TypeName)
+	}
+
+	if err := parser.EmbedAccessoryLock(dir, opts.TypeName); err != nil {
+		return nil, false, err
+	}
+
+	pkg, err := parser.ParsePackage(dir)
+	if err != nil {
+		return nil, false, err
+	}
+	return pkg, needsLock, nil
+}
+
+func findStruct(pkg *types.Package, name string) *types.Struct {
+	for _, file := range pkg.Files {
+		for _, st := range file.Structs {
+			if st.Name == name {
+				return st
+			}
+		}
+	}
+	return nil
+}
+
+// executeFromConfig runs the config-driven generation flow used when no
+// -type flag is given: it loads a .accessory.yml describing one or more
+// packages and types, and generates accessors for every one of them.
+// Explicitly-set CLI flags (-receiver, -output, -getter-style, -plugins,
+// -concurrent, -auto-embed-lock) override the corresponding per-type config
+// value.
+func executeFromConfig(fs afero.Fs, flags *flag.FlagSet, dir, configPath string) error {
+	if configPath == "" {
+		configPath = filepath.Join(dir, config.DefaultFileName)
+	}
+
+	cfg, err := config.Load(fs, configPath)
+	if err != nil {
+		return err
+	}
+
+	overrides := map[string]string{}
+	flags.Visit(func(f *flag.Flag) {
+		overrides[f.Name] = f.Value.String()
+	})
+
+	for _, pkgCfg := range cfg.Packages {
+		pkgDir := pkgCfg.Dir
+		if pkgDir == "" {
+			pkgDir = dir
+		}
+
+		pkg, err := parser.ParsePackage(pkgDir)
+		if err != nil {
+			return err
+		}
+
+		for _, typeCfg := range pkgCfg.Types {
+			opts := optionsFromConfig(typeCfg)
+
+			if v, ok := overrides["receiver"]; ok {
+				opts.Receiver = v
+			}
+			if v, ok := overrides["output"]; ok {
+				opts.Output = v
+			}
+			if v, ok := overrides["getter-style"]; ok {
+				opts.GetterStyle = v
+			}
+			if v, ok := overrides["plugins"]; ok {
+				opts.Plugins = splitPlugins(v)
+			}
+			if v, ok := overrides["concurrent"]; ok {
+				opts.Concurrent = v == "true"
+			}
+			if v, ok := overrides["auto-embed-lock"]; ok {
+				opts.AutoEmbedLock = v == "true"
+			}
+
+			if err := generateType(fs, pkgDir, pkg, opts); err != nil {
+				return fmt.Errorf("type %s in %s: %w", typeCfg.Name, pkgDir, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func optionsFromConfig(typeCfg config.TypeConfig) generator.Options {
+	skip := make(map[string]bool, len(typeCfg.Skip))
+	for _, entry := range typeCfg.Skip {
+		skip[entry] = true
+	}
+
+	return generator.Options{
+		TypeName:      typeCfg.Name,
+		Receiver:      typeCfg.Receiver,
+		Output:        typeCfg.Output,
+		GetterStyle:   typeCfg.GetterStyle,
+		GetterPattern: typeCfg.Getter,
+		SetterPattern: typeCfg.Setter,
+		Skip:          skip,
+		Imports:       typeCfg.Imports,
+		Plugins:       typeCfg.Plugins,
+		Concurrent:    typeCfg.Concurrent,
+		AutoEmbedLock: typeCfg.AutoEmbedLock,
+	}
+}
+
+// splitPlugins parses a comma-separated -plugins flag value, ignoring
+// blank entries.
+func splitPlugins(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+
+	var names []string
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 func isDir(name string) bool {
 	info, err := os.Stat(name)
 	if err != nil {
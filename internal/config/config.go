@@ -0,0 +1,77 @@
+// Package config loads the optional .accessory.yml file that drives
+// multi-type, multi-package generation without a per-type CLI invocation.
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFileName is the config file name looked up in the target
+// directory when no -type flag is given.
+const DefaultFileName = ".accessory.yml"
+
+// Config is the root of a parsed .accessory.yml file.
+type Config struct {
+	Packages []PackageConfig `yaml:"packages"`
+}
+
+// PackageConfig lists the types to generate accessors for within a single
+// package directory.
+type PackageConfig struct {
+	Dir   string       `yaml:"dir"`
+	Types []TypeConfig `yaml:"types"`
+}
+
+// TypeConfig holds per-type generation overrides.
+type TypeConfig struct {
+	Name string `yaml:"name"`
+
+	Receiver string `yaml:"receiver"`
+	Output   string `yaml:"output"`
+
+	// GetterStyle is the default getter style for this type's pointer
+	// fields, e.g. "safe". See generator.GetterStyleSafe.
+	GetterStyle string `yaml:"getterStyle"`
+
+	// Getter and Setter are text/template naming patterns applied to
+	// fields that don't set a custom name in their struct tag, e.g.
+	// "Get{{.Field}}" and "Set{{.Field}}".
+	Getter string `yaml:"getter"`
+	Setter string `yaml:"setter"`
+
+	// Skip lists "Struct.Field" entries to exclude from generation even
+	// if the field carries an accessor tag.
+	Skip []string `yaml:"skip"`
+
+	// Imports forces the import alias used for a given package name,
+	// e.g. {"time": "t"}.
+	Imports map[string]string `yaml:"imports"`
+
+	// Plugins lists extra generator plugin names to run for this type in
+	// addition to the built-in "getter" and "setter" plugins, e.g.
+	// ["builder"]. See generator.RegisterPlugin.
+	Plugins []string `yaml:"plugins"`
+
+	// Concurrent and AutoEmbedLock mirror the -concurrent and
+	// -auto-embed-lock flags for this type.
+	Concurrent    bool `yaml:"concurrent"`
+	AutoEmbedLock bool `yaml:"autoEmbedLock"`
+}
+
+// Load reads and parses the config file at path.
+func Load(fs afero.Fs, path string) (*Config, error) {
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("read config %s: %w", path, err)
+	}
+
+	cfg := &Config{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parse config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
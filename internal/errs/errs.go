@@ -0,0 +1,115 @@
+// Package errs holds the typed errors returned by internal/parser and
+// internal/generator, so callers such as cmd.Execute can render a
+// diagnostic that points at the offending source location.
+package errs
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// maxStackDepth bounds how many frames Callers records; deep stacks beyond
+// it are simply truncated.
+const maxStackDepth = 32
+
+// Stack is a call stack captured via Callers at the point a ParseError or
+// GenerateError was created, in the style of github.com/pkg/errors.
+type Stack []uintptr
+
+// Callers captures the stack of its caller, skipping this function's own
+// frame, for embedding in a ParseError or GenerateError.
+func Callers() Stack {
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(2, pcs[:])
+	return Stack(pcs[:n])
+}
+
+// StackTrace returns the captured call stack as runtime.Frames, deepest
+// call first. It is nil for a zero-value Stack.
+func (s Stack) StackTrace() []runtime.Frame {
+	if len(s) == 0 {
+		return nil
+	}
+
+	var frames []runtime.Frame
+	framesIter := runtime.CallersFrames(s)
+	for {
+		frame, more := framesIter.Next()
+		frames = append(frames, frame)
+		if !more {
+			break
+		}
+	}
+	return frames
+}
+
+// ParseError reports a problem internal/parser found while parsing a
+// package, at the position of the offending declaration.
+type ParseError struct {
+	File   string
+	Line   int
+	Column int
+	Msg    string
+	Err    error
+	Stack  Stack
+}
+
+func (e *ParseError) Error() string {
+	if e.Line == 0 && e.Column == 0 {
+		// Package-level failures (bad directory, no Go package found) have
+		// no specific declaration to point at.
+		return e.Msg
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Msg)
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// Format implements fmt.Formatter so %+v renders the captured stack
+// alongside the error message, matching github.com/pkg/errors.
+func (e *ParseError) Format(f fmt.State, verb rune) {
+	format(e, e.Stack, f, verb)
+}
+
+// GenerateError reports a problem internal/generator found while
+// generating an accessor for a specific struct field.
+type GenerateError struct {
+	File   string
+	Line   int
+	Column int
+	Struct string
+	Field  string
+	Tag    string
+	Msg    string
+	Err    error
+	Stack  Stack
+}
+
+func (e *GenerateError) Error() string {
+	pos := fmt.Sprintf("%s:%d:%d", e.File, e.Line, e.Column)
+	if e.Field == "" {
+		return fmt.Sprintf("%s: %s", pos, e.Msg)
+	}
+	return fmt.Sprintf("%s: field %s: %s", pos, e.Field, e.Msg)
+}
+
+func (e *GenerateError) Unwrap() error { return e.Err }
+
+// Format implements fmt.Formatter so %+v renders the captured stack
+// alongside the error message, matching github.com/pkg/errors.
+func (e *GenerateError) Format(f fmt.State, verb rune) {
+	format(e, e.Stack, f, verb)
+}
+
+// format is the shared fmt.Formatter body for ParseError and GenerateError:
+// %+v appends the captured stack trace, anything else falls back to Error().
+func format(err error, stack Stack, f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('+') {
+		fmt.Fprint(f, err.Error())
+		for _, frame := range stack.StackTrace() {
+			fmt.Fprintf(f, "\n\t%s\n\t\t%s:%d", frame.Function, frame.File, frame.Line)
+		}
+		return
+	}
+	fmt.Fprint(f, err.Error())
+}
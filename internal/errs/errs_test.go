@@ -0,0 +1,56 @@
+package errs
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseError_Error(t *testing.T) {
+	err := &ParseError{File: "foo.go", Line: 42, Column: 5, Msg: "bad tag"}
+
+	const want = "foo.go:42:5: bad tag"
+	if got := err.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+// TestParseError_Error_NoPosition pins that a package-level ParseError
+// (no specific Line/Column, e.g. ParsePackage's own top-level failures)
+// renders as the plain message, not a fake "dir:0:0:" position.
+func TestParseError_Error_NoPosition(t *testing.T) {
+	err := &ParseError{File: "/some/dir", Msg: "no Go package found in /some/dir"}
+
+	const want = "no Go package found in /some/dir"
+	if got := err.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestGenerateError_Format_PlusV_IncludesStack(t *testing.T) {
+	err := &GenerateError{
+		File: "foo.go", Line: 42, Column: 5, Field: "Bar",
+		Msg:   `invalid setter name "Set-Bar"`,
+		Stack: Callers(),
+	}
+
+	plain := fmt.Sprintf("%v", err)
+	if plain != err.Error() {
+		t.Errorf("%%v = %q, want %q", plain, err.Error())
+	}
+
+	verbose := fmt.Sprintf("%+v", err)
+	if !strings.HasPrefix(verbose, err.Error()) {
+		t.Errorf("%%+v = %q, want it to start with %q", verbose, err.Error())
+	}
+	if !strings.Contains(verbose, "TestGenerateError_Format_PlusV_IncludesStack") {
+		t.Errorf("%%+v = %q, want it to include the capturing test's frame", verbose)
+	}
+}
+
+func TestStack_StackTrace_EmptyForZeroValue(t *testing.T) {
+	var s Stack
+	if frames := s.StackTrace(); frames != nil {
+		t.Errorf("StackTrace() = %v, want nil for a zero-value Stack", frames)
+	}
+}
@@ -0,0 +1,130 @@
+package generator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+
+	"github.com/masaushi/accessory/internal/types"
+)
+
+// writeConcurrentSampleModule generates the thread-safe getter/setter for a
+// small Counter struct into a standalone scratch module on disk, so the
+// generated code can be compiled and exercised by the real go toolchain.
+func writeConcurrentSampleModule(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	fs := afero.NewOsFs()
+
+	pkg := &types.Package{
+		Name: "sample",
+		Dir:  dir,
+		Files: []*types.File{{
+			Structs: []*types.Struct{{
+				Name:   "Counter",
+				Embeds: []string{"accessoryLock"},
+				Fields: []*types.Field{
+					{Name: "value", DataType: "int", Tag: &types.Tag{Getter: strPtr(""), Setter: strPtr("")}},
+				},
+			}},
+		}},
+	}
+
+	if err := WriteLockHelper(fs, pkg); err != nil {
+		t.Fatalf("WriteLockHelper: %v", err)
+	}
+	if err := Generate(fs, pkg, Options{TypeName: "Counter", Concurrent: true}); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	files := map[string]string{
+		"go.mod": "module sample\n\ngo 1.21\n",
+		"counter.go": `package sample
+
+type Counter struct {
+	accessoryLock
+	value int
+}
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	return dir
+}
+
+// TestConcurrentAccessors_Race generates the accessoryLock-guarded
+// getter/setter for a Counter struct and exercises them concurrently under
+// the race detector, to pin that Options.Concurrent actually produces
+// race-free accessors rather than just locked-looking source.
+func TestConcurrentAccessors_Race(t *testing.T) {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available")
+	}
+
+	dir := writeConcurrentSampleModule(t)
+
+	const raceTest = `package sample
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestConcurrentAccess(t *testing.T) {
+	c := &Counter{}
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func(n int) {
+			defer wg.Done()
+			c.SetValue(n)
+		}(i)
+		go func() {
+			defer wg.Done()
+			_ = c.Value()
+		}()
+	}
+	wg.Wait()
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "counter_race_test.go"), []byte(raceTest), 0644); err != nil {
+		t.Fatalf("write race test: %v", err)
+	}
+
+	cmd := exec.Command(goBin, "test", "-race", "./...")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("generated accessors failed under the race detector:\n%s", out)
+	}
+}
+
+// TestConcurrentAccessors_FieldAlignment runs the fieldalignment analyzer,
+// when it's installed on PATH, over the struct embedding accessoryLock to
+// catch padding regressions in the lock helper.
+func TestConcurrentAccessors_FieldAlignment(t *testing.T) {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available")
+	}
+	toolPath, err := exec.LookPath("fieldalignment")
+	if err != nil {
+		t.Skip("fieldalignment not installed")
+	}
+
+	dir := writeConcurrentSampleModule(t)
+
+	cmd := exec.Command(goBin, "vet", "-vettool="+toolPath, "./...")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("fieldalignment flagged the generated package:\n%s", out)
+	}
+}
@@ -15,54 +15,94 @@ import (
 	"github.com/masaushi/accessory/internal/types"
 )
 
+// GetterStyleRaw returns the field's value as-is, including raw pointers.
+// GetterStyleSafe returns a nil-safe getter with a zero-value fallback for
+// pointer fields.
+const (
+	GetterStyleRaw  = ""
+	GetterStyleSafe = "safe"
+)
+
 type generator struct {
 	buf *bytes.Buffer
 }
 
+// Options configures a single call to Generate. TypeName is the only
+// required field; everything else falls back to the generator's built-in
+// defaults when left zero.
+type Options struct {
+	TypeName string
+	Output   string
+	Receiver string
+
+	// GetterStyle is the default getter generation style (GetterStyleRaw
+	// or GetterStyleSafe). A field's own `accessor:"getter=safe"` tag
+	// option always overrides it.
+	GetterStyle string
+
+	// GetterPattern and SetterPattern are text/template naming patterns,
+	// e.g. "Get{{.Field}}", applied to fields that don't set a custom
+	// name in their struct tag.
+	GetterPattern string
+	SetterPattern string
+
+	// Skip lists "Struct.Field" entries to exclude from generation even
+	// if the field carries an accessor tag.
+	Skip map[string]bool
+
+	// Imports forces the import alias used for a given package name.
+	Imports map[string]string
+
+	// Plugins lists extra plugin names to run in addition to the built-in
+	// "getter" and "setter" plugins, e.g. []string{"builder"}.
+	Plugins []string
+
+	// Concurrent makes every generated getter and setter for this type
+	// thread-safe by guarding it with the struct's embedded accessoryLock.
+	// A field's own `accessor:"...,lock"` tag option enables this
+	// independently of Concurrent.
+	Concurrent bool
+
+	// AutoEmbedLock rewrites the struct's source to embed accessoryLock
+	// when a thread-safe accessor is requested but the struct doesn't
+	// embed it yet, instead of failing generation. Generate itself doesn't
+	// read it; the caller acts on it before calling Generate (see
+	// parser.EmbedAccessoryLock).
+	AutoEmbedLock bool
+}
+
 // Generate generates a file and accessor methods in it.
-func Generate(fs afero.Fs, pkg *types.Package, typeName, output, receiverName string) error {
+func Generate(fs afero.Fs, pkg *types.Package, opts Options) error {
 	g := generator{buf: new(bytes.Buffer)}
 
-	setterGen := g.setterGenerator(receiverName)
-	getterGen := g.getterGenerator(receiverName)
+	plugins, err := resolvePlugins(opts.Plugins)
+	if err != nil {
+		return err
+	}
 
 	accessors := make([]string, 0)
 	imports := make(map[string]string)
 
 	for _, file := range pkg.Files {
 		for _, st := range file.Structs {
-			if st.Name != typeName {
+			if st.Name != opts.TypeName {
 				continue
 			}
 
-			for _, field := range st.Fields {
-				if field.Tag == nil {
-					continue
-				}
-
-				typePkg := strings.Split(strings.TrimPrefix(field.DataType, "*"), ".")[0]
-				if _, ok := imports[typePkg]; !ok {
-					for _, imp := range file.Imports {
-						if imp.Name == typePkg {
-							imports[imp.Name] = imp.PkgPath
-							break
-						}
-					}
-				}
+			ctx := &GenContext{
+				Package: pkg,
+				File:    file,
+				Options: opts,
+				imports: imports,
+			}
 
-				if field.Tag.Getter != nil {
-					getter, err := getterGen(st.Name, field)
-					if err != nil {
-						return err
-					}
-					accessors = append(accessors, getter)
+			for _, plugin := range plugins {
+				decls, err := plugin.Generate(ctx, st)
+				if err != nil {
+					return err
 				}
-				if field.Tag.Setter != nil {
-					setter, err := setterGen(st.Name, field)
-					if err != nil {
-						return err
-					}
-					accessors = append(accessors, setter)
+				for _, decl := range decls {
+					accessors = append(accessors, string(decl))
 				}
 			}
 		}
@@ -75,7 +115,7 @@ func Generate(fs afero.Fs, pkg *types.Package, typeName, output, receiverName st
 		return err
 	}
 
-	outputFile := g.outputFile(output, typeName, pkg.Dir)
+	outputFile := g.outputFile(opts.Output, opts.TypeName, pkg.Dir)
 	return afero.WriteFile(fs, outputFile, content, 0644)
 }
 
@@ -115,22 +155,41 @@ func (g *generator) write(pkgName string, importMap map[string]string, accessors
 }
 
 func (g *generator) setterGenerator(
-	receiverName string,
+	receiverName, namePattern string, concurrent bool,
 ) func(structName string, field *types.Field) (string, error) {
 	const tpl = `
 func ({{.Receiver}} *{{.Struct}}) {{.MethodName}}(val {{.Type}}) {
 	{{.Receiver}}.{{.Field}} = val
+}`
+	const lockedTpl = `
+func ({{.Receiver}} *{{.Struct}}) {{.MethodName}}(val {{.Type}}) {
+	{{.Receiver}}.mu.Lock()
+	defer {{.Receiver}}.mu.Unlock()
+	{{.Receiver}}.{{.Field}} = val
 }`
 	t := template.Must(template.New("setter").Parse(tpl))
+	lockedT := template.Must(template.New("setterLocked").Parse(lockedTpl))
 
 	return func(structName string, field *types.Field) (string, error) {
 		methodName := *field.Tag.Setter
 		if methodName == "" {
-			methodName = fmt.Sprintf("Set%s", strings.Title(field.Name))
+			var err error
+			methodName, err = defaultName(namePattern, field.Name, fmt.Sprintf("Set%s", strings.Title(field.Name)))
+			if err != nil {
+				return "", err
+			}
+		}
+		if !isValidIdentifier(methodName) {
+			return "", fmt.Errorf("invalid setter name %q", methodName)
+		}
+
+		tpl := t
+		if concurrent || field.Tag.Lock {
+			tpl = lockedT
 		}
 
 		buf := new(bytes.Buffer)
-		err := t.Execute(buf, map[string]string{
+		err := tpl.Execute(buf, map[string]string{
 			"Receiver":   g.receiverName(receiverName, structName),
 			"Struct":     structName,
 			"MethodName": methodName,
@@ -146,21 +205,130 @@ func ({{.Receiver}} *{{.Struct}}) {{.MethodName}}(val {{.Type}}) {
 }
 
 func (g *generator) getterGenerator(
-	receiverName string,
+	receiverName, defaultStyle, namePattern string, concurrent bool,
 ) func(structName string, field *types.Field) (string, error) {
-	const tpl = `
+	const rawTpl = `
+func ({{.Receiver}} *{{.Struct}}) {{.MethodName}}() {{.Type}} {
+	return {{.Receiver}}.{{.Field}}
+}`
+	const rawLockedTpl = `
+func ({{.Receiver}} *{{.Struct}}) {{.MethodName}}() {{.Type}} {
+	{{.Receiver}}.mu.RLock()
+	defer {{.Receiver}}.mu.RUnlock()
+	return {{.Receiver}}.{{.Field}}
+}`
+	const safeTpl = `
+func ({{.Receiver}} *{{.Struct}}) {{.MethodName}}() {{.Type}} {
+	if {{.Receiver}} == nil || {{.Receiver}}.{{.Field}} == nil {
+		{{.ZeroValue}}
+	}
+	return *{{.Receiver}}.{{.Field}}
+}`
+	const safeLockedTpl = `
+func ({{.Receiver}} *{{.Struct}}) {{.MethodName}}() {{.Type}} {
+	if {{.Receiver}} == nil {
+		{{.ZeroValue}}
+	}
+	{{.Receiver}}.mu.RLock()
+	defer {{.Receiver}}.mu.RUnlock()
+	if {{.Receiver}}.{{.Field}} == nil {
+		{{.ZeroValue}}
+	}
+	return *{{.Receiver}}.{{.Field}}
+}`
+	// safePtrTpl and safePtrLockedTpl cover *T fields whose pointee isn't a
+	// basic scalar (structs, slices, maps, interfaces, other named types).
+	// Unlike safeTpl, they return the pointer itself instead of a
+	// dereferenced copy: a copy would be non-addressable, so a chained call
+	// like a.GetB().GetC() wouldn't compile against GetC's pointer
+	// receiver. Only the receiver needs a nil check; the field itself can
+	// be nil and is simply returned as-is.
+	const safePtrTpl = `
 func ({{.Receiver}} *{{.Struct}}) {{.MethodName}}() {{.Type}} {
+	if {{.Receiver}} == nil {
+		return nil
+	}
 	return {{.Receiver}}.{{.Field}}
 }`
-	t := template.Must(template.New("getter").Parse(tpl))
+	const safePtrLockedTpl = `
+func ({{.Receiver}} *{{.Struct}}) {{.MethodName}}() {{.Type}} {
+	if {{.Receiver}} == nil {
+		return nil
+	}
+	{{.Receiver}}.mu.RLock()
+	defer {{.Receiver}}.mu.RUnlock()
+	return {{.Receiver}}.{{.Field}}
+}`
+	rawT := template.Must(template.New("getter").Parse(rawTpl))
+	rawLockedT := template.Must(template.New("getterLocked").Parse(rawLockedTpl))
+	safeT := template.Must(template.New("getterSafe").Parse(safeTpl))
+	safeLockedT := template.Must(template.New("getterSafeLocked").Parse(safeLockedTpl))
+	safePtrT := template.Must(template.New("getterSafePtr").Parse(safePtrTpl))
+	safePtrLockedT := template.Must(template.New("getterSafePtrLocked").Parse(safePtrLockedTpl))
 
 	return func(structName string, field *types.Field) (string, error) {
 		methodName := *field.Tag.Getter
 		if methodName == "" {
-			methodName = strings.Title(field.Name)
+			var err error
+			methodName, err = defaultName(namePattern, field.Name, strings.Title(field.Name))
+			if err != nil {
+				return "", err
+			}
 		}
 
+		if !isValidIdentifier(methodName) {
+			return "", fmt.Errorf("invalid getter name %q", methodName)
+		}
+
+		style := defaultStyle
+		if field.Tag.GetterStyle != "" {
+			style = field.Tag.GetterStyle
+		}
+		locked := concurrent || field.Tag.Lock
+
 		buf := new(bytes.Buffer)
+
+		if style == GetterStyleSafe && field.IsPointer {
+			if !isScalarElemType(field.ElemType) {
+				t := safePtrT
+				if locked {
+					t = safePtrLockedT
+				}
+				err := t.Execute(buf, map[string]string{
+					"Receiver":   g.receiverName(receiverName, structName),
+					"Struct":     structName,
+					"MethodName": methodName,
+					"Field":      field.Name,
+					"Type":       field.DataType,
+				})
+				if err != nil {
+					return "", err
+				}
+				return buf.String(), nil
+			}
+
+			t := safeT
+			if locked {
+				t = safeLockedT
+			}
+			err := t.Execute(buf, map[string]string{
+				"Receiver":   g.receiverName(receiverName, structName),
+				"Struct":     structName,
+				"MethodName": methodName,
+				"Field":      field.Name,
+				"Type":       field.ElemType,
+				"ZeroValue":  zeroValueStmt(field.ElemType),
+			})
+			if err != nil {
+				return "", err
+			}
+			return buf.String(), nil
+		}
+
+		t := rawT
+		if locked {
+			t = rawLockedT
+		}
 		err := t.Execute(buf, map[string]string{
 			"Receiver":   g.receiverName(receiverName, structName),
 			"Struct":     structName,
@@ -176,6 +344,87 @@ func ({{.Receiver}} *{{.Struct}}) {{.MethodName}}() {{.Type}} {
 	}
 }
 
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_]\w*$`)
+
+// isValidIdentifier reports whether name is a valid Go identifier, so it
+// can be used as a generated method name.
+func isValidIdentifier(name string) bool {
+	return identifierPattern.MatchString(name)
+}
+
+// defaultName computes the method name to use for a field that doesn't set
+// a custom name in its struct tag. When pattern is non-empty, it is
+// executed as a text/template with a single ".Field" value; otherwise
+// fallback is used.
+func defaultName(pattern, fieldName, fallback string) (string, error) {
+	if pattern == "" {
+		return fallback, nil
+	}
+
+	t, err := template.New("name").Parse(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid naming pattern %q: %w", pattern, err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := t.Execute(buf, map[string]string{"Field": fieldName}); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// zeroValueStmt returns the `return ...` statement that yields the zero
+// value of elemType, the pointee type of a safe-style getter's field.
+func zeroValueStmt(elemType string) string {
+	switch elemType {
+	case "string":
+		return `return ""`
+	case "bool":
+		return "return false"
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+		"float32", "float64",
+		"byte", "rune":
+		return "return 0"
+	}
+
+	switch {
+	case strings.HasPrefix(elemType, "*"),
+		strings.HasPrefix(elemType, "[]"),
+		strings.HasPrefix(elemType, "map["),
+		strings.HasPrefix(elemType, "interface{") || elemType == "any":
+		return "return nil"
+	case elemType == "time.Time":
+		return "return time.Time{}"
+	default:
+		// Named types (structs, defined scalars, etc.): declare a zero
+		// value variable so this works regardless of the type's kind.
+		return fmt.Sprintf("var zero %s\n\t\treturn zero", elemType)
+	}
+}
+
+// isScalarElemType reports whether elemType (a safe-style getter field's
+// pointee) is a basic scalar that's safe to return by value. Everything
+// else — structs, slices, maps, interfaces, and other named types the
+// parser can't further distinguish — is assumed to possibly be a struct
+// that itself has chained accessors, so the safe getter returns *elemType
+// instead of a non-addressable copy. See the safePtrTpl doc comment in
+// getterGenerator.
+func isScalarElemType(elemType string) bool {
+	switch elemType {
+	case "string", "bool",
+		"int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+		"float32", "float64",
+		"byte", "rune",
+		"time.Time":
+		return true
+	default:
+		return false
+	}
+}
+
 func (g *generator) receiverName(userInput string, structName string) string {
 	if userInput != "" {
 		// Do nothing if receiver name specified in args.
@@ -0,0 +1,262 @@
+package generator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/masaushi/accessory/internal/types"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestZeroValueStmt(t *testing.T) {
+	tests := []struct {
+		name     string
+		elemType string
+		want     string
+	}{
+		{"string", "string", `return ""`},
+		{"bool", "bool", "return false"},
+		{"int", "int", "return 0"},
+		{"float64", "float64", "return 0"},
+		{"byte", "byte", "return 0"},
+		{"pointer", "*User", "return nil"},
+		{"slice", "[]string", "return nil"},
+		{"map", "map[string]int", "return nil"},
+		{"interface", "interface{}", "return nil"},
+		{"any", "any", "return nil"},
+		{"time.Time", "time.Time", "return time.Time{}"},
+		{"named type", "Status", "var zero Status\n\t\treturn zero"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := zeroValueStmt(tt.elemType)
+			if got != tt.want {
+				t.Errorf("zeroValueStmt(%q) = %q, want %q", tt.elemType, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestGetterGenerator_SafeStyle_ScalarPointee pins the generated source for
+// the nil-safe getter style's value-return path: *T fields whose pointee
+// is a basic scalar are dereferenced, with a zero-value fallback for a nil
+// receiver or a nil field.
+func TestGetterGenerator_SafeStyle_ScalarPointee(t *testing.T) {
+	tests := []struct {
+		name  string
+		field *types.Field
+		want  string
+	}{
+		{
+			name: "pointer to scalar",
+			field: &types.Field{
+				Name: "Age", DataType: "*int", IsPointer: true, ElemType: "int",
+				Tag: &types.Tag{Getter: strPtr(""), GetterStyle: GetterStyleSafe},
+			},
+			want: "\nfunc (u *User) Age() int {\n\tif u == nil || u.Age == nil {\n\t\treturn 0\n\t}\n\treturn *u.Age\n}",
+		},
+		{
+			name: "pointer to time.Time",
+			field: &types.Field{
+				Name: "CreatedAt", DataType: "*time.Time", IsPointer: true, ElemType: "time.Time",
+				Tag: &types.Tag{Getter: strPtr(""), GetterStyle: GetterStyleSafe},
+			},
+			want: "\nfunc (u *User) CreatedAt() time.Time {\n\tif u == nil || u.CreatedAt == nil {\n\t\treturn time.Time{}\n\t}\n\treturn *u.CreatedAt\n}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := generator{buf: new(bytes.Buffer)}
+			genFn := g.getterGenerator("", GetterStyleRaw, "", false)
+
+			got, err := genFn("User", tt.field)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestGetterGenerator_SafeStyle_NonScalarPointee pins the generated source
+// for *T fields whose pointee isn't a basic scalar (struct, slice, map,
+// interface, other named type): the getter returns the pointer itself,
+// guarded only by a nil-receiver check, so a.GetB().GetC() keeps compiling
+// when GetC has a pointer receiver.
+func TestGetterGenerator_SafeStyle_NonScalarPointee(t *testing.T) {
+	tests := []struct {
+		name  string
+		field *types.Field
+		want  string
+	}{
+		{
+			name: "pointer to struct",
+			field: &types.Field{
+				Name: "Profile", DataType: "*Profile", IsPointer: true, ElemType: "Profile",
+				Tag: &types.Tag{Getter: strPtr(""), GetterStyle: GetterStyleSafe},
+			},
+			want: "\nfunc (u *User) Profile() *Profile {\n\tif u == nil {\n\t\treturn nil\n\t}\n\treturn u.Profile\n}",
+		},
+		{
+			name: "pointer to slice",
+			field: &types.Field{
+				Name: "Tags", DataType: "*[]string", IsPointer: true, ElemType: "[]string",
+				Tag: &types.Tag{Getter: strPtr(""), GetterStyle: GetterStyleSafe},
+			},
+			want: "\nfunc (u *User) Tags() *[]string {\n\tif u == nil {\n\t\treturn nil\n\t}\n\treturn u.Tags\n}",
+		},
+		{
+			name: "pointer to map",
+			field: &types.Field{
+				Name: "Meta", DataType: "*map[string]string", IsPointer: true, ElemType: "map[string]string",
+				Tag: &types.Tag{Getter: strPtr(""), GetterStyle: GetterStyleSafe},
+			},
+			want: "\nfunc (u *User) Meta() *map[string]string {\n\tif u == nil {\n\t\treturn nil\n\t}\n\treturn u.Meta\n}",
+		},
+		{
+			name: "pointer to interface",
+			field: &types.Field{
+				Name: "Value", DataType: "*interface{}", IsPointer: true, ElemType: "interface{}",
+				Tag: &types.Tag{Getter: strPtr(""), GetterStyle: GetterStyleSafe},
+			},
+			want: "\nfunc (u *User) Value() *interface{} {\n\tif u == nil {\n\t\treturn nil\n\t}\n\treturn u.Value\n}",
+		},
+		{
+			name: "pointer to named type (treated as potentially a struct)",
+			field: &types.Field{
+				Name: "Status", DataType: "*Status", IsPointer: true, ElemType: "Status",
+				Tag: &types.Tag{Getter: strPtr(""), GetterStyle: GetterStyleSafe},
+			},
+			want: "\nfunc (u *User) Status() *Status {\n\tif u == nil {\n\t\treturn nil\n\t}\n\treturn u.Status\n}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := generator{buf: new(bytes.Buffer)}
+			genFn := g.getterGenerator("", GetterStyleRaw, "", false)
+
+			got, err := genFn("User", tt.field)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestGetterGenerator_SafeStyle_NonScalarPointee_Concurrent pins the
+// RLock-wrapped variant of the pointer-return path.
+func TestGetterGenerator_SafeStyle_NonScalarPointee_Concurrent(t *testing.T) {
+	field := &types.Field{
+		Name: "Profile", DataType: "*Profile", IsPointer: true, ElemType: "Profile",
+		Tag: &types.Tag{Getter: strPtr(""), GetterStyle: GetterStyleSafe},
+	}
+
+	g := generator{buf: new(bytes.Buffer)}
+	genFn := g.getterGenerator("", GetterStyleRaw, "", true)
+
+	got, err := genFn("User", field)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "\nfunc (u *User) Profile() *Profile {\n\tif u == nil {\n\t\treturn nil\n\t}\n\tu.mu.RLock()\n\tdefer u.mu.RUnlock()\n\treturn u.Profile\n}"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// TestGetterGenerator_SafeStyle_Chaining proves the fix end to end: two
+// structs generated with the safe getter style, where the outer struct's
+// pointer-to-struct field getter returns a pointer instead of a copy, so
+// the result can be chained into the inner struct's own pointer-receiver
+// getter.
+func TestGetterGenerator_SafeStyle_Chaining(t *testing.T) {
+	g := generator{buf: new(bytes.Buffer)}
+	genFn := g.getterGenerator("", GetterStyleRaw, "", false)
+
+	outerGetter, err := genFn("Outer", &types.Field{
+		Name: "In", DataType: "*Inner", IsPointer: true, ElemType: "Inner",
+		Tag: &types.Tag{Getter: strPtr(""), GetterStyle: GetterStyleSafe},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(outerGetter, "*Inner") {
+		t.Fatalf("Outer.In must return *Inner to support chaining, got:\n%s", outerGetter)
+	}
+
+	innerGetter, err := genFn("Inner", &types.Field{
+		Name: "Name", DataType: "*string", IsPointer: true, ElemType: "string",
+		Tag: &types.Tag{Getter: strPtr(""), GetterStyle: GetterStyleSafe},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(innerGetter, "func (i *Inner) Name() string") {
+		t.Fatalf("Inner.Name must take a pointer receiver, got:\n%s", innerGetter)
+	}
+	// outerGetter returning *Inner (rather than Inner by value) is exactly
+	// what makes o.In().Name() addressable against that pointer receiver.
+}
+
+// TestGetterGenerator_SafeStyle_NilReceiver locks in that the nil-receiver
+// check comes before the nil-pointer check, so calling a safe getter on a
+// nil *User returns the zero value instead of panicking.
+func TestGetterGenerator_SafeStyle_NilReceiver(t *testing.T) {
+	field := &types.Field{
+		Name: "Age", DataType: "*int", IsPointer: true, ElemType: "int",
+		Tag: &types.Tag{Getter: strPtr(""), GetterStyle: GetterStyleSafe},
+	}
+
+	g := generator{buf: new(bytes.Buffer)}
+	genFn := g.getterGenerator("", GetterStyleRaw, "", false)
+
+	got, err := genFn("User", field)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	const wantNilCheck = "if u == nil || u.Age == nil {"
+	if !strings.Contains(got, wantNilCheck) {
+		t.Errorf("generated getter missing nil-receiver short-circuit:\n%s", got)
+	}
+}
+
+// TestGetterSetterGenerator_Concurrent pins the RLock/Lock wrapping emitted
+// when Options.Concurrent (or a field's "lock" tag) requests a
+// thread-safe accessor.
+func TestGetterSetterGenerator_Concurrent(t *testing.T) {
+	field := &types.Field{
+		Name: "Value", DataType: "int",
+		Tag: &types.Tag{Getter: strPtr(""), Setter: strPtr("")},
+	}
+
+	g := generator{buf: new(bytes.Buffer)}
+
+	gotGetter, err := g.getterGenerator("", GetterStyleRaw, "", true)("Counter", field)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantGetter := "\nfunc (c *Counter) Value() int {\n\tc.mu.RLock()\n\tdefer c.mu.RUnlock()\n\treturn c.Value\n}"
+	if gotGetter != wantGetter {
+		t.Errorf("getter:\n%s\nwant:\n%s", gotGetter, wantGetter)
+	}
+
+	gotSetter, err := g.setterGenerator("", "", true)("Counter", field)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantSetter := "\nfunc (c *Counter) SetValue(val int) {\n\tc.mu.Lock()\n\tdefer c.mu.Unlock()\n\tc.Value = val\n}"
+	if gotSetter != wantSetter {
+		t.Errorf("setter:\n%s\nwant:\n%s", gotSetter, wantSetter)
+	}
+}
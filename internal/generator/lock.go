@@ -0,0 +1,38 @@
+package generator
+
+import (
+	"fmt"
+	"go/format"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+
+	"github.com/masaushi/accessory/internal/types"
+)
+
+const lockHelperFileName = "accessory_lock.go"
+
+const lockHelperTpl = `// Code generated by accessory; DO NOT EDIT.
+
+package %s
+
+import "sync"
+
+// accessoryLock is embedded by structs that opt into thread-safe accessors
+// via the -concurrent flag or an "accessor:...,lock" field tag.
+type accessoryLock struct {
+	mu sync.RWMutex
+}
+`
+
+// WriteLockHelper writes the accessoryLock helper type that thread-safe
+// accessors rely on into pkg's directory. Callers only need this when
+// Options.Concurrent, or a field's "lock" tag option, is in use.
+func WriteLockHelper(fs afero.Fs, pkg *types.Package) error {
+	content, err := format.Source([]byte(fmt.Sprintf(lockHelperTpl, pkg.Name)))
+	if err != nil {
+		return err
+	}
+
+	return afero.WriteFile(fs, filepath.Join(pkg.Dir, lockHelperFileName), content, 0644)
+}
@@ -0,0 +1,237 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/masaushi/accessory/internal/errs"
+	"github.com/masaushi/accessory/internal/types"
+)
+
+// Decl is a single rendered top-level Go declaration, e.g. a function or a
+// type definition, ready to be written to the output file.
+type Decl string
+
+// Plugin generates declarations for a struct. Built-in plugins ("getter",
+// "setter") cover the default behaviour; additional plugins can be
+// registered with RegisterPlugin and enabled via Options.Plugins.
+type Plugin interface {
+	Name() string
+	Generate(ctx *GenContext, st *types.Struct) ([]Decl, error)
+}
+
+// GenContext is the state a Plugin needs to generate declarations for one
+// struct. Plugins read fields off it and call AddImport as needed; they
+// never touch the output buffer directly.
+type GenContext struct {
+	Package *types.Package
+	File    *types.File
+	Options Options
+
+	imports map[string]string
+}
+
+// AddImport registers the import required to reference field's data type,
+// if the type belongs to an imported package, applying any forced alias
+// from Options.Imports. field.DataType and field.ElemType are rewritten in
+// place to use that alias, so the generated method body's selector matches
+// the import line it emits.
+func (c *GenContext) AddImport(field *types.Field) {
+	field.DataType = c.resolveType(field.DataType)
+	field.ElemType = c.resolveType(field.ElemType)
+}
+
+// resolveType rewrites a package-qualified type string, e.g. "*time.Time",
+// to reference the import alias registered for its package (forced via
+// Options.Imports, or the package's own name otherwise), registering that
+// import as a side effect. Types that aren't package-qualified, or whose
+// package isn't one of File's imports, are returned unchanged.
+func (c *GenContext) resolveType(typeStr string) string {
+	prefix := ""
+	rest := typeStr
+	if strings.HasPrefix(rest, "*") {
+		prefix, rest = "*", rest[1:]
+	}
+
+	parts := strings.SplitN(rest, ".", 2)
+	if len(parts) != 2 {
+		return typeStr
+	}
+	typePkg, typeName := parts[0], parts[1]
+
+	for _, imp := range c.File.Imports {
+		if imp.Name != typePkg {
+			continue
+		}
+		alias := imp.Name
+		if forced, ok := c.Options.Imports[imp.Name]; ok {
+			alias = forced
+		}
+		c.imports[alias] = imp.PkgPath
+		return prefix + alias + "." + typeName
+	}
+
+	return typeStr
+}
+
+// Skipped reports whether "Struct.Field" was blacklisted via Options.Skip.
+func (c *GenContext) Skipped(structName, fieldName string) bool {
+	return c.Options.Skip[structName+"."+fieldName]
+}
+
+// FieldError wraps err as an *errs.GenerateError carrying field's source
+// position and the tag text that caused the failure.
+func FieldError(st *types.Struct, field *types.Field, err error) error {
+	return &errs.GenerateError{
+		File:   field.Pos.File,
+		Line:   field.Pos.Line,
+		Column: field.Pos.Column,
+		Struct: st.Name,
+		Field:  field.Name,
+		Tag:    field.Tag.Raw,
+		Msg:    err.Error(),
+		Err:    err,
+		Stack:  errs.Callers(),
+	}
+}
+
+var plugins = map[string]Plugin{}
+
+// RegisterPlugin makes a Plugin available under its Name() for use through
+// Options.Plugins. It is meant to be called from an init function.
+func RegisterPlugin(p Plugin) {
+	plugins[p.Name()] = p
+}
+
+func init() {
+	RegisterPlugin(getterPlugin{})
+	RegisterPlugin(setterPlugin{})
+	RegisterPlugin(builderPlugin{})
+}
+
+// resolvePlugins returns the built-in "getter" and "setter" plugins plus
+// any extra plugin names requested, in run order, without duplicates.
+func resolvePlugins(extra []string) ([]Plugin, error) {
+	names := append([]string{"getter", "setter"}, extra...)
+
+	seen := make(map[string]bool, len(names))
+	resolved := make([]Plugin, 0, len(names))
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		p, ok := plugins[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown plugin %q", name)
+		}
+		resolved = append(resolved, p)
+	}
+
+	return resolved, nil
+}
+
+// getterPlugin is the built-in plugin that emits getter methods for
+// getter-tagged fields.
+type getterPlugin struct{}
+
+func (getterPlugin) Name() string { return "getter" }
+
+func (getterPlugin) Generate(ctx *GenContext, st *types.Struct) ([]Decl, error) {
+	g := generator{buf: new(bytes.Buffer)}
+	genFn := g.getterGenerator(ctx.Options.Receiver, ctx.Options.GetterStyle, ctx.Options.GetterPattern, ctx.Options.Concurrent)
+
+	var decls []Decl
+	for _, field := range st.Fields {
+		if field.Tag == nil || field.Tag.Getter == nil || ctx.Skipped(st.Name, field.Name) {
+			continue
+		}
+
+		ctx.AddImport(field)
+
+		out, err := genFn(st.Name, field)
+		if err != nil {
+			return nil, FieldError(st, field, err)
+		}
+		decls = append(decls, Decl(out))
+	}
+
+	return decls, nil
+}
+
+// setterPlugin is the built-in plugin that emits setter methods for
+// setter-tagged fields.
+type setterPlugin struct{}
+
+func (setterPlugin) Name() string { return "setter" }
+
+func (setterPlugin) Generate(ctx *GenContext, st *types.Struct) ([]Decl, error) {
+	g := generator{buf: new(bytes.Buffer)}
+	genFn := g.setterGenerator(ctx.Options.Receiver, ctx.Options.SetterPattern, ctx.Options.Concurrent)
+
+	var decls []Decl
+	for _, field := range st.Fields {
+		if field.Tag == nil || field.Tag.Setter == nil || ctx.Skipped(st.Name, field.Name) {
+			continue
+		}
+
+		ctx.AddImport(field)
+
+		out, err := genFn(st.Name, field)
+		if err != nil {
+			return nil, FieldError(st, field, err)
+		}
+		decls = append(decls, Decl(out))
+	}
+
+	return decls, nil
+}
+
+// builderPlugin emits a fluent NewXBuilder().WithY(v).Build() type derived
+// from a struct's setter-tagged fields.
+type builderPlugin struct{}
+
+func (builderPlugin) Name() string { return "builder" }
+
+func (builderPlugin) Generate(ctx *GenContext, st *types.Struct) ([]Decl, error) {
+	builderType := st.Name + "Builder"
+	const receiver = "b"
+
+	var withMethods []Decl
+	for _, field := range st.Fields {
+		if field.Tag == nil || field.Tag.Setter == nil || ctx.Skipped(st.Name, field.Name) {
+			continue
+		}
+
+		ctx.AddImport(field)
+
+		withMethods = append(withMethods, Decl(fmt.Sprintf(`
+func (%s *%s) With%s(val %s) *%s {
+	%s.target.%s = val
+	return %s
+}`, receiver, builderType, strings.Title(field.Name), field.DataType, builderType, receiver, field.Name, receiver)))
+	}
+
+	if len(withMethods) == 0 {
+		return nil, nil
+	}
+
+	decls := make([]Decl, 0, len(withMethods)+2)
+	decls = append(decls, Decl(fmt.Sprintf(`
+type %s struct {
+	target %s
+}
+
+func New%s() *%s {
+	return &%s{}
+}`, builderType, st.Name, builderType, builderType, builderType)))
+	decls = append(decls, withMethods...)
+	decls = append(decls, Decl(fmt.Sprintf(`
+func (%s *%s) Build() %s {
+	return %s.target
+}`, receiver, builderType, st.Name, receiver)))
+
+	return decls, nil
+}
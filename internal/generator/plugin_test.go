@@ -0,0 +1,211 @@
+package generator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/masaushi/accessory/internal/errs"
+	"github.com/masaushi/accessory/internal/types"
+)
+
+func TestGenContext_AddImport_ForcedAlias(t *testing.T) {
+	ctx := &GenContext{
+		File: &types.File{
+			Imports: []*types.Import{
+				{Name: "time", PkgPath: "time"},
+			},
+		},
+		Options: Options{
+			Imports: map[string]string{"time": "t"},
+		},
+		imports: map[string]string{},
+	}
+
+	field := &types.Field{
+		Name: "CreatedAt", DataType: "*time.Time", IsPointer: true, ElemType: "time.Time",
+	}
+
+	ctx.AddImport(field)
+
+	if field.DataType != "*t.Time" {
+		t.Errorf("field.DataType = %q, want %q", field.DataType, "*t.Time")
+	}
+	if field.ElemType != "t.Time" {
+		t.Errorf("field.ElemType = %q, want %q", field.ElemType, "t.Time")
+	}
+	if got, want := ctx.imports["t"], "time"; got != want {
+		t.Errorf("ctx.imports[%q] = %q, want %q", "t", got, want)
+	}
+}
+
+func TestGenContext_AddImport_NoAlias(t *testing.T) {
+	ctx := &GenContext{
+		File: &types.File{
+			Imports: []*types.Import{
+				{Name: "time", PkgPath: "time"},
+			},
+		},
+		imports: map[string]string{},
+	}
+
+	field := &types.Field{Name: "CreatedAt", DataType: "time.Time", ElemType: "time.Time"}
+
+	ctx.AddImport(field)
+
+	if field.DataType != "time.Time" {
+		t.Errorf("field.DataType = %q, want unchanged %q", field.DataType, "time.Time")
+	}
+	if got, want := ctx.imports["time"], "time"; got != want {
+		t.Errorf("ctx.imports[%q] = %q, want %q", "time", got, want)
+	}
+}
+
+func TestResolvePlugins(t *testing.T) {
+	resolved, err := resolvePlugins([]string{"builder", "builder"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var names []string
+	for _, p := range resolved {
+		names = append(names, p.Name())
+	}
+	want := []string{"getter", "setter", "builder"}
+	if len(names) != len(want) {
+		t.Fatalf("resolvePlugins names = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("resolvePlugins names = %v, want %v", names, want)
+			break
+		}
+	}
+}
+
+func TestResolvePlugins_Unknown(t *testing.T) {
+	_, err := resolvePlugins([]string{"does-not-exist"})
+	if err == nil {
+		t.Fatal("expected an error for an unregistered plugin name")
+	}
+	const want = `unknown plugin "does-not-exist"`
+	if err.Error() != want {
+		t.Errorf("err = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestRegisterPlugin(t *testing.T) {
+	RegisterPlugin(fakePlugin{})
+	t.Cleanup(func() { delete(plugins, "fake") })
+
+	resolved, err := resolvePlugins([]string{"fake"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resolved) != 3 || resolved[2].Name() != "fake" {
+		t.Fatalf("resolvePlugins did not resolve the registered plugin: %v", resolved)
+	}
+}
+
+// fakePlugin is a minimal Plugin used to exercise RegisterPlugin without
+// depending on accessory's own built-in plugins.
+type fakePlugin struct{}
+
+func (fakePlugin) Name() string { return "fake" }
+
+func (fakePlugin) Generate(ctx *GenContext, st *types.Struct) ([]Decl, error) {
+	return nil, nil
+}
+
+func TestBuilderPlugin_Generate(t *testing.T) {
+	setter := ""
+	st := &types.Struct{
+		Name: "User",
+		Fields: []*types.Field{
+			{Name: "Name", DataType: "string", Tag: &types.Tag{Setter: &setter}},
+			{Name: "Age", DataType: "int", Tag: &types.Tag{Setter: &setter}},
+			{Name: "internal", DataType: "string"}, // no setter tag: excluded
+		},
+	}
+	ctx := &GenContext{
+		File:    &types.File{},
+		imports: map[string]string{},
+	}
+
+	decls, err := builderPlugin{}.Generate(ctx, st)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Decl{
+		"\ntype UserBuilder struct {\n\ttarget User\n}\n\nfunc NewUserBuilder() *UserBuilder {\n\treturn &UserBuilder{}\n}",
+		"\nfunc (b *UserBuilder) WithName(val string) *UserBuilder {\n\tb.target.Name = val\n\treturn b\n}",
+		"\nfunc (b *UserBuilder) WithAge(val int) *UserBuilder {\n\tb.target.Age = val\n\treturn b\n}",
+		"\nfunc (b *UserBuilder) Build() User {\n\treturn b.target\n}",
+	}
+	if len(decls) != len(want) {
+		t.Fatalf("got %d decls, want %d:\n%v", len(decls), len(want), decls)
+	}
+	for i := range want {
+		if decls[i] != want[i] {
+			t.Errorf("decl %d =\n%s\nwant:\n%s", i, decls[i], want[i])
+		}
+	}
+}
+
+func TestBuilderPlugin_Generate_NoSetterFields(t *testing.T) {
+	st := &types.Struct{
+		Name:   "User",
+		Fields: []*types.Field{{Name: "Name", DataType: "string"}},
+	}
+	ctx := &GenContext{File: &types.File{}, imports: map[string]string{}}
+
+	decls, err := builderPlugin{}.Generate(ctx, st)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decls != nil {
+		t.Errorf("decls = %v, want nil when no field carries a setter tag", decls)
+	}
+}
+
+// TestSetterPlugin_Generate_InvalidName pins the diagnostic rendered for an
+// invalid setter name: a *errs.GenerateError carrying field's source
+// position, wrapping the underlying validation error, with a non-empty
+// captured stack.
+func TestSetterPlugin_Generate_InvalidName(t *testing.T) {
+	invalidName := "Set-Bar"
+	st := &types.Struct{Name: "Foo"}
+	field := &types.Field{
+		Name:     "Bar",
+		DataType: "string",
+		Tag:      &types.Tag{Setter: &invalidName, Raw: "setter:Set-Bar"},
+		Pos:      types.Position{File: "foo.go", Line: 42, Column: 5},
+	}
+	st.Fields = []*types.Field{field}
+
+	ctx := &GenContext{File: &types.File{}, imports: map[string]string{}}
+
+	_, err := (setterPlugin{}).Generate(ctx, st)
+	if err == nil {
+		t.Fatal("expected an error for an invalid setter name")
+	}
+
+	const want = `foo.go:42:5: field Bar: invalid setter name "Set-Bar"`
+	if err.Error() != want {
+		t.Errorf("err.Error() = %q, want %q", err.Error(), want)
+	}
+
+	var genErr *errs.GenerateError
+	if !errors.As(err, &genErr) {
+		t.Fatalf("error is not an *errs.GenerateError: %T", err)
+	}
+	if genErr.Unwrap() == nil {
+		t.Error("GenerateError.Unwrap() = nil, want the underlying validation error")
+	}
+	if len(genErr.Stack) == 0 {
+		t.Error("GenerateError.Stack is empty, want a captured call stack")
+	}
+	if len(genErr.Stack.StackTrace()) != len(genErr.Stack) {
+		t.Error("Stack.StackTrace() frame count doesn't match the captured stack")
+	}
+}
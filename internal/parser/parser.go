@@ -0,0 +1,306 @@
+// Package parser parses a directory of Go source files into the
+// internal/types representation consumed by internal/generator.
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/masaushi/accessory/internal/errs"
+	"github.com/masaushi/accessory/internal/types"
+)
+
+const accessorTagKey = "accessor"
+
+// accessoryLockType is the name of the helper type structs must embed to
+// use thread-safe accessors. See generator.WriteLockHelper.
+const accessoryLockType = "accessoryLock"
+
+// ParsePackage parses the Go source files in dir and returns the package
+// representation used by the generator.
+func ParsePackage(dir string) (*types.Package, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, nil, parser.ParseComments)
+	if err != nil {
+		return nil, &errs.ParseError{
+			File:  dir,
+			Msg:   fmt.Sprintf("parse directory %s: %s", dir, err),
+			Err:   err,
+			Stack: errs.Callers(),
+		}
+	}
+
+	for name, astPkg := range pkgs {
+		if strings.HasSuffix(name, "_test") {
+			continue
+		}
+
+		pkg := &types.Package{Name: name, Dir: dir}
+		for filename, astFile := range astPkg.Files {
+			file, err := parseFile(fset, filename, astFile)
+			if err != nil {
+				return nil, err
+			}
+			pkg.Files = append(pkg.Files, file)
+		}
+		return pkg, nil
+	}
+
+	return nil, &errs.ParseError{
+		File:  dir,
+		Msg:   fmt.Sprintf("no Go package found in %s", dir),
+		Stack: errs.Callers(),
+	}
+}
+
+func parseFile(fset *token.FileSet, filename string, astFile *ast.File) (*types.File, error) {
+	file := &types.File{Name: filepath.Base(filename)}
+
+	for _, imp := range astFile.Imports {
+		pkgPath := strings.Trim(imp.Path.Value, `"`)
+		name := pkgPath[strings.LastIndex(pkgPath, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		file.Imports = append(file.Imports, &types.Import{Name: name, PkgPath: pkgPath})
+	}
+
+	var err error
+	ast.Inspect(astFile, func(n ast.Node) bool {
+		if err != nil {
+			return false
+		}
+
+		typeSpec, ok := n.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		structType, ok := typeSpec.Type.(*ast.StructType)
+		if !ok {
+			return true
+		}
+
+		var st *types.Struct
+		st, err = parseStruct(fset, typeSpec.Name.Name, structType)
+		if err != nil {
+			return false
+		}
+		st.Pos = position(fset, typeSpec.Pos())
+		file.Structs = append(file.Structs, st)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+func parseStruct(fset *token.FileSet, name string, structType *ast.StructType) (*types.Struct, error) {
+	st := &types.Struct{Name: name}
+
+	for _, field := range structType.Fields.List {
+		if len(field.Names) == 0 {
+			st.Embeds = append(st.Embeds, typeString(field.Type))
+			continue
+		}
+
+		if field.Tag == nil {
+			continue
+		}
+
+		dataType := typeString(field.Type)
+		_, isPointer := field.Type.(*ast.StarExpr)
+
+		tag, err := parseTag(fset, field.Tag.Pos(), field.Tag.Value)
+		if err != nil {
+			return nil, err
+		}
+		if tag == nil {
+			continue
+		}
+
+		for _, fieldName := range field.Names {
+			st.Fields = append(st.Fields, &types.Field{
+				Name:      fieldName.Name,
+				DataType:  dataType,
+				IsPointer: isPointer,
+				ElemType:  strings.TrimPrefix(dataType, "*"),
+				Tag:       tag,
+				Pos:       position(fset, fieldName.Pos()),
+			})
+		}
+	}
+
+	return st, nil
+}
+
+func position(fset *token.FileSet, pos token.Pos) types.Position {
+	p := fset.Position(pos)
+	return types.Position{File: p.Filename, Line: p.Line, Column: p.Column}
+}
+
+// parseTag parses the `accessor:"..."` struct tag. The value is a
+// comma-separated list of options:
+//
+//	getter               generate a getter with the default name
+//	getter:Name          generate a getter named Name
+//	getter=safe          generate a nil-safe getter with the default name
+//	getter=safe:Name     generate a nil-safe getter named Name
+//	setter               generate a setter with the default name
+//	setter:Name          generate a setter named Name
+//	lock                 guard the accessor with the struct's embedded
+//	                     accessoryLock (see -concurrent)
+//
+// A nil *types.Tag and nil error means the field has no accessor tag. A
+// non-nil error means the tag is present but malformed.
+func parseTag(fset *token.FileSet, pos token.Pos, raw string) (*types.Tag, error) {
+	value, ok := reflect.StructTag(strings.Trim(raw, "`")).Lookup(accessorTagKey)
+	if !ok {
+		return nil, nil
+	}
+
+	tag := &types.Tag{Raw: value}
+	for _, opt := range strings.Split(value, ",") {
+		opt = strings.TrimSpace(opt)
+
+		switch {
+		case opt == "":
+			// Tolerate trailing/leading commas.
+		case opt == "getter":
+			empty := ""
+			tag.Getter = &empty
+		case strings.HasPrefix(opt, "getter=safe"):
+			tag.GetterStyle = "safe"
+			name := strings.TrimPrefix(opt, "getter=safe")
+			name = strings.TrimPrefix(name, ":")
+			tag.Getter = &name
+		case strings.HasPrefix(opt, "getter:"):
+			name := strings.TrimPrefix(opt, "getter:")
+			tag.Getter = &name
+		case opt == "setter":
+			empty := ""
+			tag.Setter = &empty
+		case strings.HasPrefix(opt, "setter:"):
+			name := strings.TrimPrefix(opt, "setter:")
+			tag.Setter = &name
+		case opt == "lock":
+			tag.Lock = true
+		default:
+			p := fset.Position(pos)
+			return nil, &errs.ParseError{
+				File:   p.Filename,
+				Line:   p.Line,
+				Column: p.Column,
+				Msg:    fmt.Sprintf("unrecognized accessor tag option %q in `accessor:%q`", opt, value),
+				Stack:  errs.Callers(),
+			}
+		}
+	}
+
+	if tag.Getter == nil && tag.Setter == nil && !tag.Lock {
+		return nil, nil
+	}
+	return tag, nil
+}
+
+// EmbedAccessoryLock rewrites the struct named structName in dir so that it
+// embeds accessoryLock, unless it already does. It backs the -auto-embed-lock
+// flag, the alternative to failing generation when a struct opts into
+// thread-safe accessors without the embed in place.
+func EmbedAccessoryLock(dir, structName string) error {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, nil, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("parse directory %s: %w", dir, err)
+	}
+
+	for name, astPkg := range pkgs {
+		if strings.HasSuffix(name, "_test") {
+			continue
+		}
+
+		for path, astFile := range astPkg.Files {
+			found, injected := injectLockEmbed(astFile, structName)
+			if !found {
+				continue
+			}
+			if !injected {
+				return nil
+			}
+			return writeFile(fset, path, astFile)
+		}
+	}
+
+	return fmt.Errorf("struct %s not found in %s", structName, dir)
+}
+
+// injectLockEmbed adds an anonymous accessoryLock field as the struct's
+// first field, if the struct is declared in astFile and doesn't already
+// embed it. It reports whether the struct is declared in astFile and
+// whether it made a change.
+func injectLockEmbed(astFile *ast.File, structName string) (found, injected bool) {
+	ast.Inspect(astFile, func(n ast.Node) bool {
+		typeSpec, ok := n.(*ast.TypeSpec)
+		if !ok || typeSpec.Name.Name != structName {
+			return true
+		}
+		structType, ok := typeSpec.Type.(*ast.StructType)
+		if !ok {
+			return true
+		}
+		found = true
+
+		for _, field := range structType.Fields.List {
+			if len(field.Names) == 0 && typeString(field.Type) == accessoryLockType {
+				return false
+			}
+		}
+
+		embed := &ast.Field{Type: ast.NewIdent(accessoryLockType)}
+		structType.Fields.List = append([]*ast.Field{embed}, structType.Fields.List...)
+		injected = true
+		return false
+	})
+
+	return found, injected
+}
+
+func writeFile(fset *token.FileSet, path string, astFile *ast.File) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if err := printer.Fprint(f, fset, astFile); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
+
+func typeString(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return "*" + typeString(t.X)
+	case *ast.SelectorExpr:
+		return typeString(t.X) + "." + t.Sel.Name
+	case *ast.ArrayType:
+		return "[]" + typeString(t.Elt)
+	case *ast.MapType:
+		return "map[" + typeString(t.Key) + "]" + typeString(t.Value)
+	case *ast.InterfaceType:
+		return "interface{}"
+	default:
+		return fmt.Sprintf("%T", expr)
+	}
+}
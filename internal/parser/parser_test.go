@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/masaushi/accessory/internal/errs"
+)
+
+func TestParsePackage_NoGoFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := ParsePackage(dir)
+	if err == nil {
+		t.Fatal("expected an error for a directory with no Go files")
+	}
+
+	var parseErr *errs.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("error is not an *errs.ParseError: %T", err)
+	}
+	if parseErr.File != dir {
+		t.Errorf("ParseError.File = %q, want %q", parseErr.File, dir)
+	}
+	if len(parseErr.Stack) == 0 {
+		t.Error("ParseError.Stack is empty, want a captured call stack")
+	}
+}
+
+func TestParsePackage_DirNotFound(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "missing")
+
+	_, err := ParsePackage(dir)
+	if err == nil {
+		t.Fatal("expected an error for a nonexistent directory")
+	}
+
+	var parseErr *errs.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("error is not an *errs.ParseError: %T", err)
+	}
+	if parseErr.Unwrap() == nil {
+		t.Error("ParseError.Unwrap() = nil, want the underlying go/parser error")
+	}
+}
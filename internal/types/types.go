@@ -0,0 +1,98 @@
+// Package types holds the in-memory representation of a parsed Go package
+// that internal/parser produces and internal/generator consumes.
+package types
+
+// Package represents a single parsed Go package.
+type Package struct {
+	Name  string
+	Dir   string
+	Files []*File
+}
+
+// File represents a single parsed Go source file within a package.
+type File struct {
+	Name    string
+	Imports []*Import
+	Structs []*Struct
+}
+
+// Import represents an import declaration of a file.
+type Import struct {
+	Name    string
+	PkgPath string
+}
+
+// Position is the source location of a parsed declaration, used to render
+// diagnostics that point back at the offending struct or field.
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+// Struct represents a struct type declaration.
+type Struct struct {
+	Name   string
+	Fields []*Field
+
+	// Embeds lists the type names of this struct's embedded (anonymous)
+	// fields, e.g. ["accessoryLock"].
+	Embeds []string
+
+	Pos Position
+}
+
+// EmbedsType reports whether the struct embeds a field of the given type
+// name.
+func (s *Struct) EmbedsType(name string) bool {
+	for _, embed := range s.Embeds {
+		if embed == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Field represents a single field of a struct, along with the accessor
+// options requested through its struct tag.
+type Field struct {
+	Name string
+
+	// DataType is the field's type exactly as written in the source,
+	// e.g. "string", "*User", "[]byte".
+	DataType string
+
+	// IsPointer reports whether DataType is a pointer type. ElemType then
+	// holds DataType with the leading "*" stripped.
+	IsPointer bool
+	ElemType  string
+
+	Tag *Tag
+	Pos Position
+}
+
+// Tag holds the accessor options parsed out of a field's struct tag.
+type Tag struct {
+	// Getter is non-nil when a getter should be generated. An empty string
+	// means "use the default method name", otherwise it holds the custom
+	// method name.
+	Getter *string
+
+	// Setter is non-nil when a setter should be generated. An empty string
+	// means "use the default method name", otherwise it holds the custom
+	// method name.
+	Setter *string
+
+	// GetterStyle selects the getter's generation style, e.g. "safe" for a
+	// nil-safe getter with a zero-value fallback. Empty means the default
+	// raw-field getter.
+	GetterStyle string
+
+	// Lock requests a thread-safe accessor for this field: getters take an
+	// RLock, setters take a Lock, on the struct's embedded accessoryLock.
+	Lock bool
+
+	// Raw is the unparsed value of the field's `accessor:"..."` tag, kept
+	// around so error messages can quote the tag that triggered them.
+	Raw string
+}